@@ -15,6 +15,8 @@ import (
 	"strings"
 	"time"
 
+	"InstaStellar/resolvecache"
+
 	"github.com/OWASP/Amass/amass/core"
 	"github.com/OWASP/Amass/amass/dnssrv"
 	"github.com/OWASP/Amass/amass/handlers"
@@ -121,6 +123,20 @@ type Enumeration struct {
 	// The writer used to save the data operations performed
 	DataOptsWriter io.Writer
 
+	// Disables the reverse DNS sweep normally performed around each
+	// resolved address
+	NoReverseSweep bool
+
+	// Overrides DefaultPassiveSweepSize when greater than zero
+	PassiveSweepSize int
+
+	// Overrides DefaultActiveSweepSize when greater than zero
+	ActiveSweepSize int
+
+	// Caches DNS resolution and zone-cut results across enumerations.
+	// Caching is disabled when nil.
+	ResolveCache *resolvecache.Cache
+
 	// The root domain names that the enumeration will target
 	domains []string
 
@@ -217,7 +233,12 @@ func (e *Enumeration) Start(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	utils.SetDialContext(dnssrv.DialContext)
+
+	dial := dnssrv.DialContext
+	if e.ResolveCache != nil {
+		dial = e.ResolveCache.Wrap(dial)
+	}
+	utils.SetDialContext(dial)
 
 	bus := evbus.New()
 	bus.SubscribeAsync(core.OUTPUT, e.sendOutput, false)
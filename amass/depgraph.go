@@ -0,0 +1,19 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package amass
+
+import "InstaStellar/depgraph"
+
+// MergeDependencyGraph folds the graph built by this enumeration into dg,
+// the shared multi-domain dependency graph used for SPOF and
+// shared-infrastructure analysis. It is a no-op until Start has run with
+// DNS resolution enabled, since passive enumerations never build a Graph.
+func (e *Enumeration) MergeDependencyGraph(dg *depgraph.Graph) {
+	if e.Graph == nil {
+		return
+	}
+	for _, domain := range e.domains {
+		dg.Merge(domain, e.Graph)
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package amass
+
+import (
+	"math/big"
+	"net"
+	"sync"
+
+	"github.com/OWASP/Amass/amass/dnssrv"
+	boom "github.com/tylertreat/BoomFilters"
+)
+
+const (
+	// DefaultPassiveSweepSize is the number of addresses swept around a
+	// resolved IP when the enumeration is running in passive mode.
+	DefaultPassiveSweepSize = 250
+
+	// DefaultActiveSweepSize is the number of addresses swept around a
+	// resolved IP when the enumeration is running in active mode.
+	DefaultActiveSweepSize = 500
+)
+
+// sweepFilter is shared by every Enumeration in the process so that a
+// netblock discovered by one job is not re-swept by another.
+var (
+	sweepFilter     = boom.NewDefaultStableBloomFilter(1000000, 0.01)
+	sweepFilterLock sync.Mutex
+)
+
+// sweepSeen reports whether addr has already been queued for a reverse
+// sweep by any enumeration in this process. When it has not, addr is
+// recorded so that future calls (from this or any other Enumeration)
+// return true instead of repeating the work.
+func sweepSeen(addr net.IP) bool {
+	data := []byte(addr.String())
+
+	sweepFilterLock.Lock()
+	defer sweepFilterLock.Unlock()
+
+	if sweepFilter.Test(data) {
+		return true
+	}
+	sweepFilter.Add(data)
+	return false
+}
+
+// reverseSweepSize returns the number of surrounding addresses to query,
+// preferring the size configured on the Enumeration and otherwise
+// falling back to the passive/active defaults.
+func (e *Enumeration) reverseSweepSize() int {
+	if e.Active {
+		if e.ActiveSweepSize > 0 {
+			return e.ActiveSweepSize
+		}
+		return DefaultActiveSweepSize
+	}
+
+	if e.PassiveSweepSize > 0 {
+		return e.PassiveSweepSize
+	}
+	return DefaultPassiveSweepSize
+}
+
+// addrNetblock returns the netblock that addr belongs to, preferring the
+// one Amass already resolved through its ASN cache and falling back to a
+// /24 for IPv4 or a /64 for IPv6 when none was found.
+func addrNetblock(info AmassAddressInfo) *net.IPNet {
+	if info.Netblock != nil {
+		return info.Netblock
+	}
+
+	addr := info.Address
+	if ip4 := addr.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4.Mask(net.CIDRMask(24, 32)), Mask: net.CIDRMask(24, 32)}
+	}
+	return &net.IPNet{IP: addr.Mask(net.CIDRMask(64, 128)), Mask: net.CIDRMask(64, 128)}
+}
+
+// ReverseDNSSweep queries the addresses surrounding info.Address for PTR
+// records, skipping any address already swept elsewhere in the process.
+// Names discovered this way are sent to out rather than e.Output: e.Output
+// is closed by Start as soon as its data sources finish, which can easily
+// happen while a sweep kicked off near the end of a run is still in
+// flight, so the caller owns out and is responsible for draining it for as
+// long as sweeps may still be running.
+func (e *Enumeration) ReverseDNSSweep(info AmassAddressInfo, out chan<- *AmassOutput) {
+	if e.NoReverseSweep {
+		return
+	}
+
+	cidr := addrNetblock(info)
+	size := e.reverseSweepSize()
+
+	var domain string
+	if domains := e.Domains(); len(domains) > 0 {
+		domain = domains[0]
+	}
+
+	for _, addr := range utilsHosts(cidr, info.Address, size) {
+		if sweepSeen(addr) {
+			continue
+		}
+
+		name, err := dnssrv.Reverse(addr.String())
+		if err != nil || name == "" {
+			continue
+		}
+
+		out <- &AmassOutput{
+			Name:   name,
+			Domain: domain,
+			Tag:    "dns",
+			Source: "Reverse DNS Sweep",
+			Addresses: []AmassAddressInfo{{
+				Address:     addr,
+				Netblock:    cidr,
+				ASN:         info.ASN,
+				Description: info.Description,
+			}},
+		}
+	}
+}
+
+// utilsHosts returns up to size addresses from cidr, walking outward from
+// center (nearest first) in both directions and stopping at the netblock's
+// boundaries. Bounding purely on size - rather than on a host count derived
+// from the netblock's prefix length - also sidesteps the overflow an IPv6
+// /64 (or any netblock shorter than ~/56) would otherwise cause: 1<<64
+// doesn't fit in a 64-bit int.
+func utilsHosts(cidr *net.IPNet, center net.IP, size int) []net.IP {
+	var addrs []net.IP
+
+	byteLen := 4
+	addr4 := center.To4()
+	if addr4 == nil {
+		byteLen = 16
+		addr4 = center.To16()
+	}
+	centerInt := new(big.Int).SetBytes(addr4)
+
+	for offset := int64(1); len(addrs) < size; offset++ {
+		found := false
+
+		for _, sign := range [2]int64{1, -1} {
+			if len(addrs) >= size {
+				break
+			}
+
+			candidate := new(big.Int).Add(centerInt, big.NewInt(sign*offset))
+			addr := bigIntToIP(candidate, byteLen)
+			if addr == nil || !cidr.Contains(addr) {
+				continue
+			}
+
+			found = true
+			addrs = append(addrs, addr)
+		}
+
+		if !found {
+			break
+		}
+	}
+	return addrs
+}
+
+// bigIntToIP renders n as a byteLen-byte net.IP, or nil if n is negative or
+// too large to fit (i.e. it fell outside the address space entirely).
+func bigIntToIP(n *big.Int, byteLen int) net.IP {
+	if n.Sign() < 0 {
+		return nil
+	}
+
+	b := n.Bytes()
+	if len(b) > byteLen {
+		return nil
+	}
+
+	ip := make(net.IP, byteLen)
+	copy(ip[byteLen-len(b):], b)
+	return ip
+}
@@ -0,0 +1,78 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package amass
+
+import (
+	"net"
+	"testing"
+)
+
+func TestUtilsHostsIPv4WalksOutwardFromCenter(t *testing.T) {
+	center := net.ParseIP("192.0.2.10")
+	_, cidr, _ := net.ParseCIDR("192.0.2.0/24")
+
+	addrs := utilsHosts(cidr, center, 10)
+	if len(addrs) != 10 {
+		t.Fatalf("utilsHosts() returned %d addresses, want 10", len(addrs))
+	}
+
+	for _, a := range addrs {
+		if a.Equal(center) {
+			t.Fatalf("utilsHosts() returned the center address %v", center)
+		}
+		if !cidr.Contains(a) {
+			t.Fatalf("utilsHosts() returned %v, outside of %v", a, cidr)
+		}
+	}
+
+	nearest := []string{"192.0.2.9", "192.0.2.11"}
+	for _, want := range nearest {
+		found := false
+		for _, a := range addrs {
+			if a.Equal(net.ParseIP(want)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("utilsHosts() = %v, want it to include the nearest address %s", addrs, want)
+		}
+	}
+}
+
+func TestUtilsHostsIPv6SlashSixtyFourDoesNotOverflow(t *testing.T) {
+	center := net.ParseIP("2001:db8::1")
+	_, cidr, _ := net.ParseCIDR("2001:db8::/64")
+
+	addrs := utilsHosts(cidr, center, 5)
+	if len(addrs) != 5 {
+		t.Fatalf("utilsHosts() returned %d addresses for a /64, want 5 (pre-fix this overflowed to 0)", len(addrs))
+	}
+
+	for _, a := range addrs {
+		if !cidr.Contains(a) {
+			t.Fatalf("utilsHosts() returned %v, outside of %v", a, cidr)
+		}
+	}
+}
+
+func TestUtilsHostsStopsAtNetblockBoundary(t *testing.T) {
+	center := net.ParseIP("192.0.2.1")
+	_, cidr, _ := net.ParseCIDR("192.0.2.0/30") // .0-.3; asking for far more than fit should stop at the edge
+
+	addrs := utilsHosts(cidr, center, 100)
+
+	want := map[string]bool{"192.0.2.0": true, "192.0.2.2": true, "192.0.2.3": true}
+	if len(addrs) != len(want) {
+		t.Fatalf("utilsHosts() = %v, want exactly %v", addrs, want)
+	}
+	for _, a := range addrs {
+		if a.Equal(center) {
+			t.Fatalf("utilsHosts() returned the center address %v", center)
+		}
+		if !want[a.String()] {
+			t.Fatalf("utilsHosts() returned unexpected address %v, want one of %v", a, want)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package amass
+
+import (
+	"io"
+
+	"InstaStellar/viz"
+)
+
+// WriteDOT renders the graph built by this enumeration into GraphViz DOT
+// format. It must be called after Start has returned.
+func (e *Enumeration) WriteDOT(w io.Writer) error {
+	var domain string
+	if domains := e.Domains(); len(domains) > 0 {
+		domain = domains[0]
+	}
+	return viz.WriteDOT(w, domain, e.Graph)
+}
@@ -0,0 +1,196 @@
+package depgraph
+
+// SPOF is a node whose removal would disconnect one or more target domains
+// from part of their resolution chain.
+type SPOF struct {
+	Node Node
+}
+
+// ArticulationPoints returns every node that is a single point of failure:
+// removing it splits the graph (treated as undirected) into more connected
+// components than it started with. This is the standard Tarjan/Hopcroft
+// articulation point algorithm, run once per connected component.
+func (g *Graph) ArticulationPoints() []SPOF {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	disc := make(map[Node]int)
+	low := make(map[Node]int)
+	visited := make(map[Node]bool)
+	isArticulation := make(map[Node]bool)
+	timer := 0
+
+	var dfs func(u Node, parent Node, hasParent bool)
+	dfs = func(u Node, parent Node, hasParent bool) {
+		visited[u] = true
+		timer++
+		disc[u] = timer
+		low[u] = timer
+		children := 0
+
+		for _, v := range g.neighbors(u) {
+			if hasParent && v == parent {
+				continue
+			}
+			if visited[v] {
+				if disc[v] < low[u] {
+					low[u] = disc[v]
+				}
+				continue
+			}
+
+			children++
+			dfs(v, u, true)
+			if low[v] < low[u] {
+				low[u] = low[v]
+			}
+
+			if !hasParent && children > 1 {
+				isArticulation[u] = true
+			}
+			if hasParent && low[v] >= disc[u] {
+				isArticulation[u] = true
+			}
+		}
+	}
+
+	for n := range g.nodes {
+		if !visited[n] {
+			dfs(n, Node{}, false)
+		}
+	}
+
+	var spofs []SPOF
+	for n := range isArticulation {
+		spofs = append(spofs, SPOF{Node: n})
+	}
+	return spofs
+}
+
+// CNAMECycles returns every cycle of CNAME edges found in the graph, each
+// expressed as the ordered list of names that form the loop. A DNS zone
+// with a CNAME cycle can never resolve, so these are always worth
+// reporting.
+func (g *Graph) CNAMECycles() [][]string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	visited := make(map[Node]bool)
+	onStack := make(map[Node]bool)
+	stack := []Node{}
+	var cycles [][]string
+
+	var dfs func(u Node)
+	dfs = func(u Node) {
+		visited[u] = true
+		onStack[u] = true
+		stack = append(stack, u)
+
+		for _, e := range g.out[u] {
+			if e.Relation != RelCNAME {
+				continue
+			}
+			v := e.To
+
+			if onStack[v] {
+				cycle := []string{}
+				start := -1
+				for i, n := range stack {
+					if n == v {
+						start = i
+						break
+					}
+				}
+				for _, n := range stack[start:] {
+					cycle = append(cycle, n.ID)
+				}
+				cycle = append(cycle, v.ID)
+				cycles = append(cycles, cycle)
+				continue
+			}
+
+			if !visited[v] {
+				dfs(v)
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		onStack[u] = false
+	}
+
+	for n := range g.nodes {
+		if n.Type == NodeSubdomain && !visited[n] {
+			dfs(n)
+		}
+	}
+	return cycles
+}
+
+// GroupByNameserver returns, for each nameserver seen across every merged
+// domain, the set of domains that delegate to it.
+func (g *Graph) GroupByNameserver() map[string][]string {
+	return g.groupBy(NodeNS, RelNS)
+}
+
+// GroupByASN returns, for each ASN seen across every merged domain, the set
+// of domains that host at least one address in it.
+func (g *Graph) GroupByASN() map[string][]string {
+	return g.groupBy(NodeASN, RelInASN)
+}
+
+// groupBy walks every domain's subdomains for edges (possibly two hops away
+// through an IP, in the ASN case) leading to a node of kind, and returns the
+// domains grouped by the shared node's ID.
+func (g *Graph) groupBy(kind NodeType, rel Relation) map[string][]string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	groups := make(map[string]map[string]bool)
+
+	for n := range g.nodes {
+		if n.Type != NodeDomain {
+			continue
+		}
+
+		for _, shared := range g.reachableOfType(n, kind, rel) {
+			if groups[shared] == nil {
+				groups[shared] = make(map[string]bool)
+			}
+			groups[shared][n.ID] = true
+		}
+	}
+
+	result := make(map[string][]string, len(groups))
+	for shared, domains := range groups {
+		for d := range domains {
+			result[shared] = append(result[shared], d)
+		}
+	}
+	return result
+}
+
+// reachableOfType returns the IDs of every node of the given kind reachable
+// from domainNode by following resolves_to/cname/ns/in_netblock/in_asn
+// edges forward, stopping once a matching relation is used.
+func (g *Graph) reachableOfType(domainNode Node, kind NodeType, rel Relation) []string {
+	seen := make(map[Node]bool)
+	var ids []string
+
+	var walk func(u Node)
+	walk = func(u Node) {
+		if seen[u] {
+			return
+		}
+		seen[u] = true
+
+		for _, e := range g.out[u] {
+			if e.To.Type == kind && e.Relation == rel {
+				ids = append(ids, e.To.ID)
+				continue
+			}
+			walk(e.To)
+		}
+	}
+	walk(domainNode)
+	return ids
+}
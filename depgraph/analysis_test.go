@@ -0,0 +1,75 @@
+package depgraph
+
+import "testing"
+
+func TestCNAMECyclesDetectsCycle(t *testing.T) {
+	g := NewGraph()
+	a := Node{Type: NodeSubdomain, ID: "a"}
+	b := Node{Type: NodeSubdomain, ID: "b"}
+	g.addEdge(Edge{From: a, To: b, Relation: RelCNAME})
+	g.addEdge(Edge{From: b, To: a, Relation: RelCNAME})
+
+	cycles := g.CNAMECycles()
+	if len(cycles) != 1 {
+		t.Fatalf("CNAMECycles() = %v, want exactly one cycle", cycles)
+	}
+
+	// The DFS starts from whichever subdomain map iteration yields first, so
+	// either rotation of the same loop is a correct answer.
+	got := cycles[0]
+	want := []string{"a", "b", "a"}
+	if len(got) > 0 && got[0] == "b" {
+		want = []string{"b", "a", "b"}
+	}
+	if len(got) != len(want) {
+		t.Fatalf("cycle = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("cycle = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestCNAMECyclesNoCycleInAChain(t *testing.T) {
+	g := NewGraph()
+	a := Node{Type: NodeSubdomain, ID: "a"}
+	b := Node{Type: NodeSubdomain, ID: "b"}
+	c := Node{Type: NodeSubdomain, ID: "c"}
+	g.addEdge(Edge{From: a, To: b, Relation: RelCNAME})
+	g.addEdge(Edge{From: b, To: c, Relation: RelCNAME})
+
+	if cycles := g.CNAMECycles(); len(cycles) != 0 {
+		t.Fatalf("CNAMECycles() = %v, want none for an acyclic chain", cycles)
+	}
+}
+
+func TestArticulationPoints(t *testing.T) {
+	// a - b - c: b is the only cut vertex in this chain.
+	g := NewGraph()
+	a := Node{Type: NodeSubdomain, ID: "a"}
+	b := Node{Type: NodeSubdomain, ID: "b"}
+	c := Node{Type: NodeSubdomain, ID: "c"}
+	g.addEdge(Edge{From: a, To: b, Relation: RelResolvesTo})
+	g.addEdge(Edge{From: b, To: c, Relation: RelResolvesTo})
+
+	spofs := g.ArticulationPoints()
+	if len(spofs) != 1 || spofs[0].Node != b {
+		t.Fatalf("ArticulationPoints() = %v, want only %v", spofs, b)
+	}
+}
+
+func TestArticulationPointsNoneInATriangle(t *testing.T) {
+	// a - b - c - a: every node has an alternate path, so none is a SPOF.
+	g := NewGraph()
+	a := Node{Type: NodeSubdomain, ID: "a"}
+	b := Node{Type: NodeSubdomain, ID: "b"}
+	c := Node{Type: NodeSubdomain, ID: "c"}
+	g.addEdge(Edge{From: a, To: b, Relation: RelResolvesTo})
+	g.addEdge(Edge{From: b, To: c, Relation: RelResolvesTo})
+	g.addEdge(Edge{From: c, To: a, Relation: RelResolvesTo})
+
+	if spofs := g.ArticulationPoints(); len(spofs) != 0 {
+		t.Fatalf("ArticulationPoints() = %v, want none for a triangle", spofs)
+	}
+}
@@ -0,0 +1,38 @@
+package depgraph
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// Report is the JSON shape printed by the "blazeRecon analyze" subcommand.
+type Report struct {
+	SPOFs        []SPOF              `json:"spofs"`
+	CNAMECycles  [][]string          `json:"cname_cycles"`
+	ByNameserver map[string][]string `json:"by_nameserver"`
+	ByASN        map[string][]string `json:"by_asn"`
+}
+
+// Analyze runs every analysis routine over g and returns the combined
+// report.
+func Analyze(g *Graph) Report {
+	return Report{
+		SPOFs:        g.ArticulationPoints(),
+		CNAMECycles:  g.CNAMECycles(),
+		ByNameserver: g.GroupByNameserver(),
+		ByASN:        g.GroupByASN(),
+	}
+}
+
+// RunCLI implements the "blazeRecon analyze" subcommand: it loads the
+// persisted dependency graph and prints its analysis report as JSON.
+func (s *Store) RunCLI(args []string) error {
+	g, err := s.Load()
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(Analyze(g))
+}
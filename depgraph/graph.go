@@ -0,0 +1,212 @@
+// Package depgraph builds a cross-domain dependency graph out of the
+// per-domain graphs Amass produces during enumeration, and runs
+// transdep-style analysis over it: single points of failure, CNAME cycles,
+// and shared-infrastructure grouping across every domain enumerated so far.
+package depgraph
+
+import (
+	"strconv"
+	"sync"
+
+	"InstaStellar/depgraph/graphwalk"
+
+	"github.com/OWASP/Amass/amass/handlers"
+)
+
+func asnID(asn int) string {
+	return strconv.Itoa(asn)
+}
+
+// NodeType identifies what a Node represents.
+type NodeType string
+
+// The node types the graph is built from.
+const (
+	NodeDomain    NodeType = "domain"
+	NodeSubdomain NodeType = "subdomain"
+	NodeNS        NodeType = "nameserver"
+	NodeIP        NodeType = "ip"
+	NodeNetblock  NodeType = "netblock"
+	NodeASN       NodeType = "asn"
+)
+
+// Relation identifies how two nodes are related.
+type Relation string
+
+// The edge relations the graph is built from.
+const (
+	RelResolvesTo Relation = "resolves_to"
+	RelCNAME      Relation = "cname"
+	RelNS         Relation = "ns"
+	RelInNetblock Relation = "in_netblock"
+	RelInASN      Relation = "in_asn"
+)
+
+// Node is a single entity in the dependency graph.
+type Node struct {
+	Type NodeType
+	ID   string // e.g. a name, an IP, a CIDR, or an ASN number as a string
+}
+
+// Edge is a directed relation between two nodes.
+type Edge struct {
+	From     Node
+	To       Node
+	Relation Relation
+}
+
+// Graph is a multi-domain dependency graph, merged incrementally as each
+// domain finishes enumeration.
+type Graph struct {
+	mu    sync.RWMutex
+	nodes map[Node]bool
+	edges map[Edge]bool
+	out   map[Node][]Edge // outgoing edges, keyed by source node
+	in    map[Node][]Edge // incoming edges, keyed by destination node
+
+	// pendingNodes and pendingEdges accumulate what's been added since the
+	// last Drain, so a caller persisting after every Merge writes only the
+	// delta instead of the whole graph each time.
+	pendingNodes []Node
+	pendingEdges []Edge
+}
+
+// NewGraph returns an empty Graph.
+func NewGraph() *Graph {
+	return &Graph{
+		nodes: make(map[Node]bool),
+		edges: make(map[Edge]bool),
+		out:   make(map[Node][]Edge),
+		in:    make(map[Node][]Edge),
+	}
+}
+
+func (g *Graph) addNode(n Node) {
+	if g.nodes[n] {
+		return
+	}
+	g.nodes[n] = true
+	g.pendingNodes = append(g.pendingNodes, n)
+}
+
+func (g *Graph) addEdge(e Edge) {
+	g.addNode(e.From)
+	g.addNode(e.To)
+	if g.edges[e] {
+		return
+	}
+	g.edges[e] = true
+	g.out[e.From] = append(g.out[e.From], e)
+	g.in[e.To] = append(g.in[e.To], e)
+	g.pendingEdges = append(g.pendingEdges, e)
+}
+
+// Drain returns every node and edge added since the last call to Drain (or
+// since the graph was created) and resets the pending set.
+func (g *Graph) Drain() ([]Node, []Edge) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	nodes, edges := g.pendingNodes, g.pendingEdges
+	g.pendingNodes, g.pendingEdges = nil, nil
+	return nodes, edges
+}
+
+// Merge walks hg, the graph built by one domain's enumeration, and folds
+// its subdomains, CNAME aliases, nameservers, addresses, netblocks and ASNs
+// into g.
+func (g *Graph) Merge(domain string, hg *handlers.Graph) {
+	if hg == nil {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	domainNode := Node{Type: NodeDomain, ID: domain}
+	g.addNode(domainNode)
+
+	for _, sub := range graphwalk.Subdomains(hg, domain) {
+		subNode := Node{Type: NodeSubdomain, ID: sub}
+		g.addEdge(Edge{From: domainNode, To: subNode, Relation: RelResolvesTo})
+
+		for _, cname := range graphwalk.CNAMERecords(hg, sub) {
+			// The CNAME target is itself a subdomain node, in the same
+			// id-space as subNode above - not a distinct NodeCNAME kind -
+			// so that a chain of CNAMEs (or a cycle) connects through
+			// g.out/g.in instead of dead-ending at an unreachable leaf.
+			g.addEdge(Edge{
+				From:     subNode,
+				To:       Node{Type: NodeSubdomain, ID: cname},
+				Relation: RelCNAME,
+			})
+		}
+
+		for _, ns := range graphwalk.NSRecords(hg, sub) {
+			g.addEdge(Edge{
+				From:     subNode,
+				To:       Node{Type: NodeNS, ID: ns},
+				Relation: RelNS,
+			})
+		}
+
+		for _, addr := range graphwalk.Addresses(hg, sub) {
+			ipNode := Node{Type: NodeIP, ID: addr}
+			g.addEdge(Edge{From: subNode, To: ipNode, Relation: RelResolvesTo})
+
+			if netblock := graphwalk.Netblock(hg, addr); netblock != "" {
+				g.addEdge(Edge{
+					From:     ipNode,
+					To:       Node{Type: NodeNetblock, ID: netblock},
+					Relation: RelInNetblock,
+				})
+			}
+
+			if asn, _ := graphwalk.ASNInfo(hg, addr); asn != 0 {
+				g.addEdge(Edge{
+					From:     ipNode,
+					To:       Node{Type: NodeASN, ID: asnID(asn)},
+					Relation: RelInASN,
+				})
+			}
+		}
+	}
+}
+
+// Nodes returns every node currently in the graph.
+func (g *Graph) Nodes() []Node {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	nodes := make([]Node, 0, len(g.nodes))
+	for n := range g.nodes {
+		nodes = append(nodes, n)
+	}
+	return nodes
+}
+
+// Edges returns every edge currently in the graph.
+func (g *Graph) Edges() []Edge {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	var edges []Edge
+	for _, es := range g.out {
+		edges = append(edges, es...)
+	}
+	return edges
+}
+
+// neighbors returns the nodes reachable from n by a single edge in either
+// direction, which is what the SPOF/connectivity analysis treats as
+// "adjacent" regardless of the relation's direction.
+func (g *Graph) neighbors(n Node) []Node {
+	var neighbors []Node
+	for _, e := range g.out[n] {
+		neighbors = append(neighbors, e.To)
+	}
+	for _, e := range g.in[n] {
+		neighbors = append(neighbors, e.From)
+	}
+	return neighbors
+}
@@ -0,0 +1,40 @@
+// Package graphwalk centralizes the handful of handlers.Graph accessors that
+// depgraph, sinks and viz each need to walk a completed enumeration's graph.
+// Amass's real handlers.Graph API couldn't be verified against upstream
+// source in this environment, so every call site funnels through here
+// instead of calling the methods directly in three places - if the actual
+// signatures differ, there is exactly one file to fix.
+package graphwalk
+
+import "github.com/OWASP/Amass/amass/handlers"
+
+// Subdomains returns every subdomain g resolved under domain.
+func Subdomains(g *handlers.Graph, domain string) []string {
+	return g.Subdomains(domain)
+}
+
+// CNAMERecords returns the CNAME targets g recorded for name.
+func CNAMERecords(g *handlers.Graph, name string) []string {
+	return g.CNAMERecords(name)
+}
+
+// NSRecords returns the nameservers g recorded for name.
+func NSRecords(g *handlers.Graph, name string) []string {
+	return g.NSRecords(name)
+}
+
+// Addresses returns the IP addresses g resolved name to.
+func Addresses(g *handlers.Graph, name string) []string {
+	return g.Addresses(name)
+}
+
+// Netblock returns the CIDR g associated with addr, or "" if none was found.
+func Netblock(g *handlers.Graph, addr string) string {
+	return g.Netblock(addr)
+}
+
+// ASNInfo returns the ASN number and description g associated with addr, or
+// (0, "") if none was found.
+func ASNInfo(g *handlers.Graph, addr string) (int, string) {
+	return g.ASNInfo(addr)
+}
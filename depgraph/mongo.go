@@ -0,0 +1,97 @@
+package depgraph
+
+import (
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// NodesCollection and EdgesCollection hold the dependency graph alongside
+// the enumeration results already written to Mongo.
+const (
+	NodesCollection = "depgraph_nodes"
+	EdgesCollection = "depgraph_edges"
+)
+
+// Store persists a Graph to Mongo and reloads it across process restarts.
+type Store struct {
+	session *mgo.Session
+}
+
+// NewStore returns a Store backed by the given Mongo session.
+func NewStore(session *mgo.Session) *Store {
+	return &Store{session: session}
+}
+
+func (s *Store) nodes() *mgo.Collection {
+	return s.session.DB("test").C(NodesCollection)
+}
+
+func (s *Store) edges() *mgo.Collection {
+	return s.session.DB("test").C(EdgesCollection)
+}
+
+// Persist upserts every node and edge added to g since the last call to
+// Persist (or Load), so that the global dependency graph survives process
+// restarts and can be reconstructed with Load, without re-writing the whole
+// graph on every call.
+func (s *Store) Persist(g *Graph) error {
+	nodes, edges := g.Drain()
+
+	for _, n := range nodes {
+		key := bson.M{"type": n.Type, "id": n.ID}
+		if _, err := s.nodes().Upsert(key, bson.M{"$set": key}); err != nil {
+			return err
+		}
+	}
+
+	for _, e := range edges {
+		key := bson.M{
+			"from_type": e.From.Type, "from_id": e.From.ID,
+			"to_type": e.To.Type, "to_id": e.To.ID,
+			"relation": e.Relation,
+		}
+		if _, err := s.edges().Upsert(key, bson.M{"$set": key}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Load rebuilds a Graph from every node and edge previously persisted.
+func (s *Store) Load() (*Graph, error) {
+	g := NewGraph()
+
+	var nodeDocs []struct {
+		Type NodeType `bson:"type"`
+		ID   string   `bson:"id"`
+	}
+	if err := s.nodes().Find(nil).All(&nodeDocs); err != nil {
+		return nil, err
+	}
+	for _, d := range nodeDocs {
+		g.addNode(Node{Type: d.Type, ID: d.ID})
+	}
+
+	var edgeDocs []struct {
+		FromType NodeType `bson:"from_type"`
+		FromID   string   `bson:"from_id"`
+		ToType   NodeType `bson:"to_type"`
+		ToID     string   `bson:"to_id"`
+		Relation Relation `bson:"relation"`
+	}
+	if err := s.edges().Find(nil).All(&edgeDocs); err != nil {
+		return nil, err
+	}
+	for _, d := range edgeDocs {
+		g.addEdge(Edge{
+			From:     Node{Type: d.FromType, ID: d.FromID},
+			To:       Node{Type: d.ToType, ID: d.ToID},
+			Relation: d.Relation,
+		})
+	}
+
+	// Everything just loaded is already persisted; clear it from the pending
+	// set so the next Persist writes only what is added after this.
+	g.Drain()
+	return g, nil
+}
@@ -5,19 +5,28 @@ import (
 	"fmt"
 	"log"
 	"math/rand"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
 	"sync"
+	"syscall"
 	"time"
 
+	"InstaStellar/depgraph"
 	"InstaStellar/messagebus"
 	consumer "InstaStellar/messagebus/consumer"
+	"InstaStellar/resolvecache"
+	"InstaStellar/scheduler"
+	"InstaStellar/sinks"
+	"InstaStellar/tracker"
+	"InstaStellar/viz"
 
 	"github.com/OWASP/Amass/amass"
 	mgo "gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 )
 
-const TimeOut = 7 * time.Second
-
 type Person struct {
 	Name   string
 	Domain string
@@ -25,6 +34,46 @@ type Person struct {
 
 var mongoSession *mgo.Session
 
+var trackerStore *tracker.Store
+
+// depGraph is the process-wide, multi-domain dependency graph merged from
+// every enumeration that completes with DNS resolution enabled.
+var depGraph = depgraph.NewGraph()
+
+var depStore *depgraph.Store
+
+// activeSink is where every enumeration result is written. It defaults to
+// Mongo alone but can fan out to additional sinks; see sinks.FromEnv.
+var activeSink sinks.Sink
+
+// resolveCache persists DNS resolution and zone-cut results across
+// enumerations, cutting query volume for second-level domains that keep
+// reappearing across message-bus jobs.
+var resolveCache *resolvecache.Cache
+
+// resolveCachePath is where the resolver cache's BoltDB file is kept.
+const resolveCachePath = "./resolve_cache.db"
+
+// trackerHTTPAddr is where the diff endpoint (GET /diff?domain=...&since=7d)
+// and the resolver cache stats endpoint (GET /cache/stats) are served.
+const trackerHTTPAddr = ":8090"
+
+// defaultPoolSize is used when BLAZERECON_POOL_SIZE isn't set.
+const defaultPoolSize = 4
+
+// Priority and deadline given to a job at submit time. messagebus.MessagePasser
+// doesn't carry either itself, so they're derived here from whether the
+// domain has been enumerated before: a first-time-seen domain is more
+// valuable to resolve quickly than a rescan of one we already have data for,
+// so it jumps the queue and gets more time to finish.
+const (
+	firstSeenPriority = 5
+	rescanPriority    = 1
+
+	firstSeenDeadline = 5 * time.Minute
+	rescanDeadline    = 2 * time.Minute
+)
+
 func initializeMongo() {
 	session, err := mgo.Dial("localhost")
 	mongoSession = session
@@ -36,63 +85,98 @@ func initializeMongo() {
 	// Optional. Switch the session to a monotonic behavior.
 	mongoSession.SetMode(mgo.Monotonic, true)
 
-}
-
-type MongoStruct struct {
-	name   string `json:"name" bson:"name"`
-	domain string `json:"domain" bson:"domain"`
-}
-
-const CollectionNames = "DOMAINS"
+	trackerStore = tracker.NewStore(mongoSession)
+	depStore = depgraph.NewStore(mongoSession)
 
-func IngestInMongo(dataToIngest Response) {
-
-	c := mongoSession.DB("test").C(CollectionNames)
+	activeSink, err = sinks.FromEnv(mongoSession, trackerStore)
+	if err != nil {
+		panic(err)
+	}
 
-	upsertdata := bson.M{"$push": bson.M{"data": dataToIngest.dataSet}}
-	_, err := c.Upsert(bson.M{"_id": dataToIngest.id}, upsertdata)
+	resolveCache, err = resolvecache.Open(resolveCachePath)
 	if err != nil {
-		log.Fatal(err)
+		panic(err)
 	}
+}
 
+func poolSize() int {
+	if v := os.Getenv("BLAZERECON_POOL_SIZE"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			return n
+		}
+	}
+	return defaultPoolSize
 }
 
-func MarkFinished(_id bson.ObjectId) {
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "track" {
+		initializeMongo()
+		if err := trackerStore.RunCLI(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
 
-	c := mongoSession.DB("test").C(CollectionNames)
-	upsertdata := bson.M{"$set": bson.M{"status": "DONE"}}
-	_, err := c.Upsert(bson.M{"_id": _id}, upsertdata)
-	if err != nil {
-		log.Fatal(err)
+	if len(os.Args) > 1 && os.Args[1] == "analyze" {
+		initializeMongo()
+		if err := depStore.RunCLI(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
 	}
 
-}
+	if len(os.Args) > 1 && os.Args[1] == "viz" {
+		initializeMongo()
+		if err := viz.RunCLI(trackerStore, os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
 
-func main() {
 	initializeMongo()
+	defer activeSink.Close()
+	defer resolveCache.Close()
 
-	noOfWorkerPools := 1
-	wg := sync.WaitGroup{}
-	wg.Add(1)
-	channel := make(chan Response)
-	inChannel := make(chan messagebus.MessagePasser)
+	go func() {
+		mux := http.NewServeMux()
+		mux.Handle("/diff", trackerStore.HTTPHandler())
+		mux.Handle("/cache/stats", resolveCache.StatsHandler())
 
-	for i := 0; i < noOfWorkerPools; i++ {
-		go EnqueueDomain(inChannel, channel)
-	}
+		log.Println("serving diffs and cache stats on", trackerHTTPAddr)
+		log.Fatal(http.ListenAndServe(trackerHTTPAddr, mux))
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGTERM, syscall.SIGINT)
+	go func() {
+		sig := <-sigCh
+		log.Println("received", sig, "- draining in-flight jobs")
+		cancel()
+	}()
 
+	sched := scheduler.New(poolSize(), runJob)
+
+	inChannel := make(chan messagebus.MessagePasser)
 	go consumer.Consume(inChannel)
 
 	go func() {
-
-		for {
-			response := <-channel
-			IngestInMongo(response)
+		for msg := range inChannel {
+			priority, deadline := jobPriorityAndDeadline(msg)
+			sched.Submit(scheduler.Job{Message: msg, Priority: priority, Deadline: deadline})
 		}
 	}()
 
-	wg.Wait()
+	sched.Run(ctx)
+}
 
+// jobPriorityAndDeadline derives a Job's scheduling hints from whether its
+// domain has been enumerated before.
+func jobPriorityAndDeadline(msg messagebus.MessagePasser) (int, time.Duration) {
+	if trackerStore.Seen(msg.Domain) {
+		return rescanPriority, rescanDeadline
+	}
+	return firstSeenPriority, firstSeenDeadline
 }
 
 type MessagePasser struct {
@@ -100,45 +184,101 @@ type MessagePasser struct {
 	Domain string
 }
 
-type Response struct {
-	id      bson.ObjectId
-	running bool
-	dataSet amass.AmassOutput
-	domain  string
-}
-
-func EnqueueDomain(inChannel <-chan messagebus.MessagePasser, response chan<- Response) {
-
-	for domainName := range inChannel {
-		ctx, _ := context.WithTimeout(context.Background(), TimeOut)
-		fmt.Println("Target", domainName)
-
-		enum := amass.NewEnumeration()
-		enum.Passive = true
-		// enum.Active = true
-
-		go func() {
-
-			for result := range enum.Output {
+// runJob runs one enumeration job to completion, acking it (MarkFinished)
+// only after the enumeration and every reverse sweep have finished and each
+// result has been handed to the sinks - so a crash mid-job leaves it unacked
+// and it gets redelivered rather than lost. A failed Ingest is logged and
+// does not hold back the ack. It implements scheduler.Handler.
+func runJob(ctx context.Context, job scheduler.Job) error {
+	domainName := job.Message
+	fmt.Println("Target", domainName)
+
+	enum := amass.NewEnumeration()
+	// Passive must be false: it's what makes Start populate enum.Graph, and
+	// the depgraph merge, Neo4j sink and WriteDOT below all depend on that
+	// graph existing.
+	enum.Passive = false
+	enum.ResolveCache = resolveCache
+
+	// Reverse-sweep results arrive on sweepOut, which runJob owns, rather
+	// than being fed back into enum.Output: Start closes that channel as
+	// soon as its data sources finish, which can race a sweep still in
+	// flight. sweepWG tracks the outstanding sweeps so sweepOut is only
+	// closed once every one of them has stopped sending.
+	sweepOut := make(chan *amass.AmassOutput)
+	var sweepWG sync.WaitGroup
+
+	outputDone := make(chan struct{})
+	go func() {
+		defer close(outputDone)
+
+		for result := range enum.Output {
+			err := activeSink.Ingest(sinks.Result{
+				ID:     bson.ObjectIdHex(domainName.Id),
+				Domain: domainName.Domain,
+				Output: *result,
+			})
+			if err != nil {
+				log.Println("Ingest error:", err)
+			}
 
-				response <- Response{
-					bson.ObjectIdHex(domainName.Id),
-					false,
-					*result,
-					domainName.Domain,
+			if !enum.NoReverseSweep {
+				for _, addr := range result.Addresses {
+					sweepWG.Add(1)
+					go func(addr amass.AmassAddressInfo) {
+						defer sweepWG.Done()
+						enum.ReverseDNSSweep(addr, sweepOut)
+					}(addr)
 				}
+			}
+		}
+	}()
 
+	sweepDone := make(chan struct{})
+	go func() {
+		defer close(sweepDone)
+
+		for result := range sweepOut {
+			err := activeSink.Ingest(sinks.Result{
+				ID:     bson.ObjectIdHex(domainName.Id),
+				Domain: domainName.Domain,
+				Output: *result,
+			})
+			if err != nil {
+				log.Println("Ingest error:", err)
 			}
+		}
+	}()
 
-		}()
+	// Seed the default pseudo-random number generator
+	rand.Seed(time.Now().UTC().UnixNano())
+	enum.AddDomain(domainName.Domain)
+	if err := enum.Start(ctx); err != nil {
+		return err
+	}
+	<-outputDone
+
+	// Every sweep goroutine launched above has already been counted in
+	// sweepWG by the time outputDone closes, so waiting on it here and
+	// then closing sweepOut is safe: no further sends can race the close.
+	sweepWG.Wait()
+	close(sweepOut)
+	<-sweepDone
+
+	if gs, ok := activeSink.(sinks.GraphSink); ok && enum.Graph != nil {
+		if err := gs.IngestGraph(domainName.Domain, enum.Graph); err != nil {
+			log.Println("IngestGraph error:", err)
+		}
+	}
 
-		// Seed the default pseudo-random number generator
-		rand.Seed(time.Now().UTC().UnixNano())
-		enum.AddDomain(domainName.Domain)
-		enum.Start(ctx)
-		// enum.Pause()
-		MarkFinished(bson.ObjectIdHex(domainName.Id))
-		fmt.Println("Done!", domainName)
+	enum.MergeDependencyGraph(depGraph)
+	if err := depStore.Persist(depGraph); err != nil {
+		log.Println("depgraph persist error:", err)
+	}
 
+	if err := activeSink.MarkFinished(bson.ObjectIdHex(domainName.Id)); err != nil {
+		return err
 	}
+	fmt.Println("Done!", domainName)
+	return nil
 }
@@ -0,0 +1,213 @@
+// Package resolvecache sits in front of Amass's DNS lookups and persists
+// answers to a local BoltDB file, so that the same second-level domains
+// reappearing across message-bus jobs (shared CDN/nameserver infra, in
+// particular) don't cost a fresh query every time.
+package resolvecache
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	bolt "github.com/boltdb/bolt"
+)
+
+var (
+	answersBucket  = []byte("answers")
+	zoneCutsBucket = []byte("zonecuts")
+)
+
+const (
+	// DefaultPositiveTTL is how long a successful answer is cached when
+	// the cache wasn't opened with an explicit override.
+	DefaultPositiveTTL = 24 * time.Hour
+
+	// DefaultNegativeTTL is how long a negative (NXDOMAIN/NODATA) answer
+	// is cached when its SOA minimum doesn't say otherwise.
+	DefaultNegativeTTL = 5 * time.Minute
+)
+
+// Stats are cumulative counters for observability.
+type Stats struct {
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Evictions uint64 `json:"evictions"`
+}
+
+// Cache is a BoltDB-backed cache of DNS answers and zone-cut lookups.
+type Cache struct {
+	db          *bolt.DB
+	positiveTTL time.Duration
+	negativeTTL time.Duration
+
+	mu    sync.Mutex
+	stats Stats
+}
+
+// Open returns a Cache backed by the BoltDB file at path, using the default
+// TTLs.
+func Open(path string) (*Cache, error) {
+	return OpenWithTTL(path, DefaultPositiveTTL, DefaultNegativeTTL)
+}
+
+// OpenWithTTL returns a Cache backed by the BoltDB file at path, using the
+// given positive and negative TTLs as the default when an answer doesn't
+// specify its own (e.g. via an SOA minimum).
+func OpenWithTTL(path string, positiveTTL, negativeTTL time.Duration) (*Cache, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(answersBucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucketIfNotExists(zoneCutsBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &Cache{db: db, positiveTTL: positiveTTL, negativeTTL: negativeTTL}, nil
+}
+
+// Close releases the underlying BoltDB file.
+func (c *Cache) Close() error {
+	return c.db.Close()
+}
+
+// Stats returns a snapshot of the cache's cumulative counters.
+func (c *Cache) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+type answerEntry struct {
+	Answer    []byte    `json:"answer,omitempty"`
+	Negative  bool      `json:"negative"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func answerKey(qname string, qtype uint16) []byte {
+	return []byte(fmt.Sprintf("%s|%d", strings.ToLower(qname), qtype))
+}
+
+// GetAnswer returns the cached wire-format answer for (qname, qtype), and
+// whether it represents a negative (NXDOMAIN/NODATA) result. ok is false on
+// a miss or an expired entry.
+func (c *Cache) GetAnswer(qname string, qtype uint16) (answer []byte, negative bool, ok bool) {
+	var entry answerEntry
+	found := false
+
+	c.db.View(func(tx *bolt.Tx) error {
+		raw := tx.Bucket(answersBucket).Get(answerKey(qname, qtype))
+		if raw == nil {
+			return nil
+		}
+		if err := decode(raw, &entry); err != nil {
+			return err
+		}
+		found = true
+		return nil
+	})
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !found {
+		c.stats.Misses++
+		return nil, false, false
+	}
+	if time.Now().After(entry.ExpiresAt) {
+		c.stats.Misses++
+		c.stats.Evictions++
+		return nil, false, false
+	}
+
+	c.stats.Hits++
+	return entry.Answer, entry.Negative, true
+}
+
+// SetAnswer records the wire-format answer for (qname, qtype). When
+// negative is true and soaMinimum is greater than zero, soaMinimum is used
+// as the TTL instead of the cache's negative default, per RFC 2308.
+func (c *Cache) SetAnswer(qname string, qtype uint16, answer []byte, negative bool, soaMinimum time.Duration) error {
+	ttl := c.positiveTTL
+	if negative {
+		ttl = c.negativeTTL
+		if soaMinimum > 0 {
+			ttl = soaMinimum
+		}
+	}
+
+	entry := answerEntry{Answer: answer, Negative: negative, ExpiresAt: time.Now().Add(ttl)}
+	raw, err := encode(entry)
+	if err != nil {
+		return err
+	}
+
+	return c.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(answersBucket).Put(answerKey(qname, qtype), raw)
+	})
+}
+
+type zoneCutEntry struct {
+	Nameservers []string  `json:"nameservers"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// ZoneCut returns the cached nameservers for zone, if present and unexpired.
+func (c *Cache) ZoneCut(zone string) (nameservers []string, ok bool) {
+	var entry zoneCutEntry
+	found := false
+
+	c.db.View(func(tx *bolt.Tx) error {
+		raw := tx.Bucket(zoneCutsBucket).Get([]byte(strings.ToLower(zone)))
+		if raw == nil {
+			return nil
+		}
+		if err := decode(raw, &entry); err != nil {
+			return err
+		}
+		found = true
+		return nil
+	})
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !found {
+		c.stats.Misses++
+		return nil, false
+	}
+	if time.Now().After(entry.ExpiresAt) {
+		c.stats.Misses++
+		c.stats.Evictions++
+		return nil, false
+	}
+
+	c.stats.Hits++
+	return entry.Nameservers, true
+}
+
+// SetZoneCut records the nameservers that answer for zone, for ttl.
+func (c *Cache) SetZoneCut(zone string, nameservers []string, ttl time.Duration) error {
+	if ttl <= 0 {
+		ttl = c.positiveTTL
+	}
+
+	entry := zoneCutEntry{Nameservers: nameservers, ExpiresAt: time.Now().Add(ttl)}
+	raw, err := encode(entry)
+	if err != nil {
+		return err
+	}
+
+	return c.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(zoneCutsBucket).Put([]byte(strings.ToLower(zone)), raw)
+	})
+}
@@ -0,0 +1,11 @@
+package resolvecache
+
+import "encoding/json"
+
+func encode(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func decode(raw []byte, v interface{}) error {
+	return json.Unmarshal(raw, v)
+}
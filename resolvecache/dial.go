@@ -0,0 +1,166 @@
+package resolvecache
+
+import (
+	"context"
+	"net"
+
+	"github.com/miekg/dns"
+)
+
+// DialContextFunc matches the signature utils.SetDialContext expects.
+type DialContextFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// Wrap returns a DialContextFunc that serves cached answers for repeat
+// queries and otherwise dials through next, recording whatever comes back
+// for next time. It only understands the single-message-per-write/read
+// shape UDP resolvers use; TCP fallbacks (zone transfers, truncated
+// responses) are passed through uncached.
+func (c *Cache) Wrap(next DialContextFunc) DialContextFunc {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		conn, err := next(ctx, network, address)
+		if err != nil {
+			return nil, err
+		}
+		if network != "udp" && network != "udp4" && network != "udp6" {
+			return conn, nil
+		}
+		return &cachingConn{Conn: conn, cache: c}, nil
+	}
+}
+
+// cachingConn wraps a UDP connection to a resolver, answering from cache
+// when the outgoing query matches an unexpired entry and otherwise letting
+// the write/read pass through, recording the response it sees.
+type cachingConn struct {
+	net.Conn
+	cache *Cache
+
+	query  *dns.Msg
+	cached []byte
+}
+
+func (cc *cachingConn) Write(b []byte) (int, error) {
+	msg := new(dns.Msg)
+	if err := msg.Unpack(b); err != nil || len(msg.Question) != 1 {
+		cc.query = nil
+		return cc.Conn.Write(b)
+	}
+	cc.query = msg
+
+	q := msg.Question[0]
+
+	// NS lookups are zone-cut queries and are served from their own cache,
+	// keyed by zone, rather than the generic per-(qname,qtype) answer
+	// cache: both are populated from the same wire traffic, but a zone cut
+	// is looked up by callers that only have the zone name on hand.
+	if q.Qtype == dns.TypeNS {
+		if nameservers, ok := cc.cache.ZoneCut(q.Name); ok {
+			if packed, err := synthesizeNSReply(msg, q, nameservers); err == nil {
+				cc.cached = packed
+				return len(b), nil
+			}
+		}
+		return cc.Conn.Write(b)
+	}
+
+	answer, _, ok := cc.cache.GetAnswer(q.Name, q.Qtype)
+	if !ok {
+		return cc.Conn.Write(b)
+	}
+
+	reply := new(dns.Msg)
+	if err := reply.Unpack(answer); err != nil {
+		return cc.Conn.Write(b)
+	}
+	reply.Id = msg.Id
+	packed, err := reply.Pack()
+	if err != nil {
+		return cc.Conn.Write(b)
+	}
+
+	cc.cached = packed
+	return len(b), nil
+}
+
+// synthesizeNSReply builds a wire-format reply to query carrying an NS
+// record for each of nameservers, for the ZoneCut cache hit path above.
+func synthesizeNSReply(query *dns.Msg, q dns.Question, nameservers []string) ([]byte, error) {
+	reply := new(dns.Msg)
+	reply.SetReply(query)
+
+	for _, ns := range nameservers {
+		reply.Answer = append(reply.Answer, &dns.NS{
+			Hdr: dns.RR_Header{
+				Name:   q.Name,
+				Rrtype: dns.TypeNS,
+				Class:  dns.ClassINET,
+				Ttl:    uint32(DefaultPositiveTTL.Seconds()),
+			},
+			Ns: ns,
+		})
+	}
+	return reply.Pack()
+}
+
+func (cc *cachingConn) Read(b []byte) (int, error) {
+	if cc.cached != nil {
+		n := copy(b, cc.cached)
+		cc.cached = nil
+		return n, nil
+	}
+
+	n, err := cc.Conn.Read(b)
+	if err == nil && cc.query != nil {
+		cc.recordAnswer(b[:n])
+	}
+	return n, err
+}
+
+func (cc *cachingConn) recordAnswer(raw []byte) {
+	reply := new(dns.Msg)
+	if err := reply.Unpack(raw); err != nil || len(reply.Question) != 1 {
+		return
+	}
+
+	q := reply.Question[0]
+
+	if q.Qtype == dns.TypeNS {
+		cc.recordZoneCut(q.Name, reply)
+		return
+	}
+
+	negative := reply.Rcode == dns.RcodeNameError || len(reply.Answer) == 0
+
+	var soaMinimum uint32
+	for _, rr := range reply.Ns {
+		if soa, ok := rr.(*dns.SOA); ok {
+			soaMinimum = soa.Minimum
+			break
+		}
+	}
+
+	cc.cache.SetAnswer(q.Name, q.Qtype, raw, negative, secondsToDuration(soaMinimum))
+}
+
+// recordZoneCut extracts the NS records from reply and, if there are any,
+// caches them as the zone-cut for zone.
+func (cc *cachingConn) recordZoneCut(zone string, reply *dns.Msg) {
+	var nameservers []string
+	minTTL := ^uint32(0)
+
+	for _, rr := range reply.Answer {
+		ns, ok := rr.(*dns.NS)
+		if !ok {
+			continue
+		}
+		nameservers = append(nameservers, ns.Ns)
+		if ns.Hdr.Ttl < minTTL {
+			minTTL = ns.Hdr.Ttl
+		}
+	}
+	if len(nameservers) == 0 {
+		return
+	}
+
+	cc.cache.SetZoneCut(zone, nameservers, secondsToDuration(minTTL))
+}
@@ -0,0 +1,20 @@
+package resolvecache
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+func secondsToDuration(seconds uint32) time.Duration {
+	return time.Duration(seconds) * time.Second
+}
+
+// StatsHandler serves the cache's hit/miss/eviction counters as JSON, for
+// wiring into an operator's existing HTTP mux.
+func (c *Cache) StatsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(c.Stats())
+	})
+}
@@ -0,0 +1,30 @@
+package scheduler
+
+// jobQueue is a container/heap.Interface ordering jobs by Priority
+// (highest first) and, among equal priorities, by submission order.
+type jobQueue []Job
+
+func (q jobQueue) Len() int { return len(q) }
+
+func (q jobQueue) Less(i, j int) bool {
+	if q[i].Priority != q[j].Priority {
+		return q[i].Priority > q[j].Priority
+	}
+	return q[i].submitted.Before(q[j].submitted)
+}
+
+func (q jobQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *jobQueue) Push(x interface{}) {
+	*q = append(*q, x.(Job))
+}
+
+func (q *jobQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	job := old[n-1]
+	*q = old[:n-1]
+	return job
+}
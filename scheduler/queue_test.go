@@ -0,0 +1,35 @@
+package scheduler
+
+import (
+	"container/heap"
+	"testing"
+	"time"
+)
+
+func TestJobQueueOrdersByPriorityThenSubmission(t *testing.T) {
+	now := time.Now()
+
+	q := &jobQueue{}
+	heap.Init(q)
+	heap.Push(q, Job{Priority: 1, submitted: now})
+	heap.Push(q, Job{Priority: 5, submitted: now.Add(time.Second)})
+	heap.Push(q, Job{Priority: 5, submitted: now})
+	heap.Push(q, Job{Priority: 0, submitted: now})
+
+	want := []Job{
+		{Priority: 5, submitted: now},
+		{Priority: 5, submitted: now.Add(time.Second)},
+		{Priority: 1, submitted: now},
+		{Priority: 0, submitted: now},
+	}
+
+	for i, w := range want {
+		if q.Len() == 0 {
+			t.Fatalf("queue emptied early at index %d, want %+v", i, w)
+		}
+		got := heap.Pop(q).(Job)
+		if got.Priority != w.Priority || !got.submitted.Equal(w.submitted) {
+			t.Fatalf("pop %d = %+v, want %+v", i, got, w)
+		}
+	}
+}
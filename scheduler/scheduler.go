@@ -0,0 +1,157 @@
+// Package scheduler replaces a fixed-size worker loop with a bounded,
+// priority-aware one: at most N jobs run at a time, higher-priority jobs
+// jump ahead of lower-priority ones, each job gets its own deadline instead
+// of one process-wide constant, and a cancelled root context drains queued
+// and in-flight work instead of losing it.
+//
+// messagebus.MessagePasser doesn't carry Priority or Deadline fields, so
+// Job.Priority and Job.Deadline are populated by the caller at Submit time;
+// see main.go's jobPriorityAndDeadline for how this program derives them.
+package scheduler
+
+import (
+	"container/heap"
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"InstaStellar/messagebus"
+)
+
+// DefaultDeadline is used for a job that doesn't specify its own.
+const DefaultDeadline = 2 * time.Minute
+
+// MaxAttempts bounds how many times in total (counting the first try) a job
+// is run while its handler keeps returning an error, so a job that can never
+// succeed doesn't loop forever.
+const MaxAttempts = 3
+
+// Job is a unit of scheduled work: a message-bus payload plus the
+// scheduling metadata needed to run it.
+type Job struct {
+	Message  messagebus.MessagePasser
+	Priority int
+	Deadline time.Duration
+
+	// Attempt counts how many times this job has been run, starting at 0
+	// for the first try. Submit callers should leave it zero.
+	Attempt int
+
+	submitted time.Time
+}
+
+// Handler runs a single Job. ctx is cancelled once the job's Deadline
+// elapses or the Scheduler is draining; the handler should return promptly
+// when it is.
+type Handler func(ctx context.Context, job Job) error
+
+// Scheduler bounds how many jobs run concurrently, runs higher-priority
+// jobs first, and enforces each job's own deadline.
+type Scheduler struct {
+	sem     chan struct{}
+	handler Handler
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	queue  jobQueue
+	closed bool
+
+	wg sync.WaitGroup
+}
+
+// New returns a Scheduler that runs at most size jobs concurrently,
+// dispatching each to handler.
+func New(size int, handler Handler) *Scheduler {
+	s := &Scheduler{
+		sem:     make(chan struct{}, size),
+		handler: handler,
+	}
+	s.cond = sync.NewCond(&s.mu)
+	return s
+}
+
+// Submit enqueues a job. Higher Priority values run first; among equal
+// priorities, earlier submissions run first. A zero Deadline is replaced
+// with DefaultDeadline.
+func (s *Scheduler) Submit(job Job) {
+	if job.Deadline <= 0 {
+		job.Deadline = DefaultDeadline
+	}
+	job.submitted = time.Now()
+
+	s.mu.Lock()
+	heap.Push(&s.queue, job)
+	s.mu.Unlock()
+	s.cond.Signal()
+}
+
+// Run dispatches queued jobs to the handler, respecting the concurrency
+// bound, until ctx is cancelled. Once cancelled, Run stops waiting for new
+// jobs to arrive but still drains whatever is already queued - each
+// dispatched job was itself handed a context derived from ctx, so it is
+// already being told to wind down - before Run itself returns. This is what
+// lets a SIGTERM-triggered shutdown flush queued and in-flight work instead
+// of losing it.
+//
+// A job whose handler returns an error is requeued until it has been run
+// MaxAttempts times, so a
+// crash or transient failure mid-job gets retried instead of silently
+// acking a job that never finished; once a job's handler succeeds, it is the
+// handler's own responsibility to have durably recorded that (e.g. via
+// MarkFinished) before returning nil.
+func (s *Scheduler) Run(ctx context.Context) {
+	go func() {
+		<-ctx.Done()
+		s.mu.Lock()
+		s.closed = true
+		s.mu.Unlock()
+		s.cond.Broadcast()
+	}()
+
+	for {
+		job, ok := s.next()
+		if !ok {
+			break
+		}
+
+		s.sem <- struct{}{}
+		s.wg.Add(1)
+		go func(job Job) {
+			defer s.wg.Done()
+			defer func() { <-s.sem }()
+
+			jobCtx, cancel := context.WithTimeout(ctx, job.Deadline)
+			defer cancel()
+
+			if err := s.handler(jobCtx, job); err != nil {
+				job.Attempt++
+				if job.Attempt < MaxAttempts && ctx.Err() == nil {
+					log.Println("scheduler: job failed, requeueing (attempt", job.Attempt+1, "of", MaxAttempts, "):", err)
+					s.Submit(job)
+				} else {
+					log.Println("scheduler: job failed and will not be retried:", err)
+				}
+			}
+		}(job)
+	}
+
+	s.wg.Wait()
+}
+
+// next blocks until a job is queued, returning ok=false once Run has been
+// told to stop and the queue has been fully drained.
+func (s *Scheduler) next() (Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for {
+		if s.queue.Len() > 0 {
+			return heap.Pop(&s.queue).(Job), true
+		}
+		if s.closed {
+			return Job{}, false
+		}
+		s.cond.Wait()
+	}
+}
@@ -0,0 +1,91 @@
+package sinks
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"InstaStellar/tracker"
+
+	mgo "gopkg.in/mgo.v2"
+)
+
+// FromEnv builds the Sink fan-out described by the BLAZERECON_SINKS
+// environment variable, a comma-separated list drawn from: mongo,
+// elasticsearch, neo4j, kafka, jsonl. It defaults to "mongo" alone so
+// existing deployments keep working without any configuration changes.
+//
+// Each sink reads its own connection details from the environment:
+//
+//	BLAZERECON_ES_URL, BLAZERECON_ES_INDEX       (default index "blazerecon")
+//	BLAZERECON_NEO4J_URL
+//	BLAZERECON_KAFKA_BROKERS, BLAZERECON_KAFKA_TOPIC
+//	BLAZERECON_JSONL_PATH                        (default "./blazerecon.jsonl")
+func FromEnv(session *mgo.Session, store *tracker.Store) (Sink, error) {
+	names := os.Getenv("BLAZERECON_SINKS")
+	if names == "" {
+		names = "mongo"
+	}
+
+	var built []Sink
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(strings.ToLower(name))
+		if name == "" {
+			continue
+		}
+
+		sink, err := newSink(name, session, store)
+		if err != nil {
+			return nil, err
+		}
+		built = append(built, sink)
+	}
+
+	return NewMultiSink(built...), nil
+}
+
+func newSink(name string, session *mgo.Session, store *tracker.Store) (Sink, error) {
+	switch name {
+	case "mongo":
+		return NewMongoSink(store), nil
+
+	case "elasticsearch", "elastic":
+		url := os.Getenv("BLAZERECON_ES_URL")
+		if url == "" {
+			return nil, fmt.Errorf("sinks: BLAZERECON_ES_URL is required for the elasticsearch sink")
+		}
+		index := os.Getenv("BLAZERECON_ES_INDEX")
+		if index == "" {
+			index = "blazerecon"
+		}
+		return NewElasticSink(url, index), nil
+
+	case "neo4j":
+		url := os.Getenv("BLAZERECON_NEO4J_URL")
+		if url == "" {
+			return nil, fmt.Errorf("sinks: BLAZERECON_NEO4J_URL is required for the neo4j sink")
+		}
+		return NewNeo4jSink(url), nil
+
+	case "kafka":
+		brokers := os.Getenv("BLAZERECON_KAFKA_BROKERS")
+		if brokers == "" {
+			return nil, fmt.Errorf("sinks: BLAZERECON_KAFKA_BROKERS is required for the kafka sink")
+		}
+		topic := os.Getenv("BLAZERECON_KAFKA_TOPIC")
+		if topic == "" {
+			topic = "blazerecon"
+		}
+		return NewKafkaSink(strings.Split(brokers, ","), topic)
+
+	case "jsonl":
+		path := os.Getenv("BLAZERECON_JSONL_PATH")
+		if path == "" {
+			path = "./blazerecon.jsonl"
+		}
+		return NewJSONLSink(path)
+
+	default:
+		return nil, fmt.Errorf("sinks: unknown sink %q", name)
+	}
+}
@@ -0,0 +1,85 @@
+package sinks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+// ElasticSink bulk-indexes results into Elasticsearch so they can be
+// searched across every enumerated domain instead of just looked up by id.
+type ElasticSink struct {
+	url    string // e.g. http://localhost:9200
+	index  string
+	client *http.Client
+}
+
+// NewElasticSink returns a Sink that bulk-indexes into the given index at
+// url.
+func NewElasticSink(url, index string) *ElasticSink {
+	return &ElasticSink{url: url, index: index, client: http.DefaultClient}
+}
+
+func (e *ElasticSink) Ingest(r Result) error {
+	meta, err := json.Marshal(bson.M{"index": bson.M{"_index": e.index, "_id": r.ID.Hex() + "-" + r.Output.Name}})
+	if err != nil {
+		return err
+	}
+	doc, err := json.Marshal(bson.M{
+		"job_id": r.ID.Hex(),
+		"domain": r.Domain,
+		"name":   r.Output.Name,
+		"tag":    r.Output.Tag,
+		"source": r.Output.Source,
+	})
+	if err != nil {
+		return err
+	}
+
+	return e.bulk(append(append(meta, '\n'), append(doc, '\n')...))
+}
+
+// MarkFinished marks every document Ingest indexed for id as done. Ingest
+// gives each name its own document, keyed by "<job>-<name>", so there is no
+// single "<job>" document to update by _id - this updates by query instead,
+// against the job_id field every one of those documents carries.
+func (e *ElasticSink) MarkFinished(id bson.ObjectId) error {
+	body, err := json.Marshal(bson.M{
+		"query":  bson.M{"term": bson.M{"job_id": id.Hex()}},
+		"script": bson.M{"source": "ctx._source.status = 'DONE'", "lang": "painless"},
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := e.client.Post(e.url+"/"+e.index+"/_update_by_query", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("sinks: elasticsearch update_by_query failed with status %s", resp.Status)
+	}
+	return nil
+}
+
+func (e *ElasticSink) bulk(body []byte) error {
+	resp, err := e.client.Post(e.url+"/_bulk", "application/x-ndjson", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("sinks: elasticsearch bulk request failed with status %s", resp.Status)
+	}
+	return nil
+}
+
+func (e *ElasticSink) Close() error {
+	return nil
+}
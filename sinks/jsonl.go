@@ -0,0 +1,56 @@
+package sinks
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+// JSONLSink appends one JSON object per line to a file, for pipelines that
+// want to tail or batch-load results without standing up a database.
+type JSONLSink struct {
+	mu   sync.Mutex
+	file *os.File
+	enc  *json.Encoder
+}
+
+// NewJSONLSink opens (creating if necessary) the file at path for appending.
+func NewJSONLSink(path string) (*JSONLSink, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &JSONLSink{file: f, enc: json.NewEncoder(f)}, nil
+}
+
+type jsonlRecord struct {
+	Event  string        `json:"event"`
+	ID     bson.ObjectId `json:"id"`
+	Domain string        `json:"domain,omitempty"`
+	Name   string        `json:"name,omitempty"`
+}
+
+func (j *JSONLSink) Ingest(r Result) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.enc.Encode(jsonlRecord{
+		Event:  "ingest",
+		ID:     r.ID,
+		Domain: r.Domain,
+		Name:   r.Output.Name,
+	})
+}
+
+func (j *JSONLSink) MarkFinished(id bson.ObjectId) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.enc.Encode(jsonlRecord{Event: "finished", ID: id})
+}
+
+func (j *JSONLSink) Close() error {
+	return j.file.Close()
+}
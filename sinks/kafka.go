@@ -0,0 +1,60 @@
+package sinks
+
+import (
+	"encoding/json"
+
+	"github.com/Shopify/sarama"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// KafkaSink re-publishes results onto a topic so downstream consumers (e.g.
+// a SIEM or another message-bus pipeline) can fan off the same enumeration
+// stream without touching Mongo.
+type KafkaSink struct {
+	topic    string
+	producer sarama.SyncProducer
+}
+
+// NewKafkaSink returns a Sink that publishes to topic on the given brokers.
+func NewKafkaSink(brokers []string, topic string) (*KafkaSink, error) {
+	config := sarama.NewConfig()
+	config.Producer.Return.Successes = true
+
+	producer, err := sarama.NewSyncProducer(brokers, config)
+	if err != nil {
+		return nil, err
+	}
+	return &KafkaSink{topic: topic, producer: producer}, nil
+}
+
+type kafkaMessage struct {
+	Event  string        `json:"event"`
+	ID     bson.ObjectId `json:"id"`
+	Domain string        `json:"domain,omitempty"`
+	Name   string        `json:"name,omitempty"`
+}
+
+func (k *KafkaSink) publish(msg kafkaMessage) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = k.producer.SendMessage(&sarama.ProducerMessage{
+		Topic: k.topic,
+		Value: sarama.ByteEncoder(payload),
+	})
+	return err
+}
+
+func (k *KafkaSink) Ingest(r Result) error {
+	return k.publish(kafkaMessage{Event: "ingest", ID: r.ID, Domain: r.Domain, Name: r.Output.Name})
+}
+
+func (k *KafkaSink) MarkFinished(id bson.ObjectId) error {
+	return k.publish(kafkaMessage{Event: "finished", ID: id})
+}
+
+func (k *KafkaSink) Close() error {
+	return k.producer.Close()
+}
@@ -0,0 +1,48 @@
+package sinks
+
+import (
+	"sync"
+
+	"InstaStellar/tracker"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+// MongoSink is the original ingestion path, now expressed as a Sink. It
+// delegates the actual writes to tracker.Store so enumeration history stays
+// available for diffing regardless of which other sinks are enabled.
+type MongoSink struct {
+	store *tracker.Store
+
+	mu      sync.Mutex
+	domains map[bson.ObjectId]string
+}
+
+// NewMongoSink returns a Sink that writes snapshots through store.
+func NewMongoSink(store *tracker.Store) *MongoSink {
+	return &MongoSink{
+		store:   store,
+		domains: make(map[bson.ObjectId]string),
+	}
+}
+
+func (m *MongoSink) Ingest(r Result) error {
+	m.mu.Lock()
+	m.domains[r.ID] = r.Domain
+	m.mu.Unlock()
+
+	return m.store.Ingest(r.Domain, r.ID, &r.Output)
+}
+
+func (m *MongoSink) MarkFinished(id bson.ObjectId) error {
+	m.mu.Lock()
+	domain := m.domains[id]
+	delete(m.domains, id)
+	m.mu.Unlock()
+
+	return m.store.Finish(domain, id)
+}
+
+func (m *MongoSink) Close() error {
+	return nil
+}
@@ -0,0 +1,142 @@
+package sinks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"InstaStellar/depgraph/graphwalk"
+
+	"github.com/OWASP/Amass/amass/handlers"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Neo4jSink writes a subdomain/IP/ASN/netblock graph per domain, so that the
+// relationships Amass discovers can be queried with Cypher across many
+// enumerated domains instead of being trapped inside one Mongo document.
+//
+// It targets the Neo4j 4.x+ HTTP transactional endpoint
+// (/db/{database}/tx/commit) and $-prefixed Cypher parameters; neither
+// exists on Neo4j 3.x, which used /db/data/transaction/commit and
+// curly-brace parameters instead.
+//
+// It only implements GraphSink: the per-name Ingest stream doesn't carry
+// enough relationship information on its own, so nodes and edges are
+// materialized once, from the completed handlers.Graph, in IngestGraph.
+type Neo4jSink struct {
+	url      string // e.g. http://localhost:7474
+	database string
+	client   *http.Client
+}
+
+// defaultNeo4jDatabase is Neo4j 4.x's default database name.
+const defaultNeo4jDatabase = "neo4j"
+
+// NewNeo4jSink returns a GraphSink that writes to the Neo4j HTTP transaction
+// endpoint at url, against the default database.
+func NewNeo4jSink(url string) *Neo4jSink {
+	return &Neo4jSink{url: url, database: defaultNeo4jDatabase, client: http.DefaultClient}
+}
+
+func (n *Neo4jSink) Ingest(r Result) error            { return nil }
+func (n *Neo4jSink) MarkFinished(bson.ObjectId) error { return nil }
+func (n *Neo4jSink) Close() error                     { return nil }
+
+// IngestGraph walks graph and MERGEs its domains, subdomains, CNAMEs, name
+// servers, addresses, netblocks and ASNs in as nodes, with edges for
+// resolves_to, cname, ns, in_netblock and in_asn.
+func (n *Neo4jSink) IngestGraph(domain string, graph *handlers.Graph) error {
+	var stmts []cypherStatement
+
+	stmts = append(stmts, cypherStatement{
+		Statement:  "MERGE (:Domain {name: $name})",
+		Parameters: bson.M{"name": domain},
+	})
+
+	for _, sub := range graphwalk.Subdomains(graph, domain) {
+		stmts = append(stmts, cypherStatement{
+			Statement: "MERGE (d:Domain {name: $domain}) " +
+				"MERGE (s:Subdomain {name: $name}) " +
+				"MERGE (d)-[:RESOLVES_TO]->(s)",
+			Parameters: bson.M{"domain": domain, "name": sub},
+		})
+
+		for _, cname := range graphwalk.CNAMERecords(graph, sub) {
+			stmts = append(stmts, cypherStatement{
+				Statement: "MERGE (s:Subdomain {name: $name}) " +
+					"MERGE (c:Subdomain {name: $cname}) " +
+					"MERGE (s)-[:CNAME]->(c)",
+				Parameters: bson.M{"name": sub, "cname": cname},
+			})
+		}
+
+		for _, ns := range graphwalk.NSRecords(graph, sub) {
+			stmts = append(stmts, cypherStatement{
+				Statement: "MERGE (s:Subdomain {name: $name}) " +
+					"MERGE (n:Nameserver {name: $ns}) " +
+					"MERGE (s)-[:NS]->(n)",
+				Parameters: bson.M{"name": sub, "ns": ns},
+			})
+		}
+
+		for _, addr := range graphwalk.Addresses(graph, sub) {
+			netblock := graphwalk.Netblock(graph, addr)
+			asn, desc := graphwalk.ASNInfo(graph, addr)
+
+			stmts = append(stmts, cypherStatement{
+				Statement: "MERGE (s:Subdomain {name: $name}) " +
+					"MERGE (a:Address {ip: $addr}) " +
+					"MERGE (s)-[:RESOLVES_TO]->(a)",
+				Parameters: bson.M{"name": sub, "addr": addr},
+			})
+
+			if netblock != "" {
+				stmts = append(stmts, cypherStatement{
+					Statement: "MERGE (a:Address {ip: $addr}) " +
+						"MERGE (nb:Netblock {cidr: $cidr}) " +
+						"MERGE (a)-[:IN_NETBLOCK]->(nb)",
+					Parameters: bson.M{"addr": addr, "cidr": netblock},
+				})
+			}
+
+			if asn != 0 {
+				stmts = append(stmts, cypherStatement{
+					Statement: "MERGE (a:Address {ip: $addr}) " +
+						"MERGE (asn:ASN {number: $asn, description: $desc}) " +
+						"MERGE (a)-[:IN_ASN]->(asn)",
+					Parameters: bson.M{"addr": addr, "asn": asn, "desc": desc},
+				})
+			}
+		}
+	}
+
+	return n.commit(stmts)
+}
+
+type cypherStatement struct {
+	Statement  string `json:"statement"`
+	Parameters bson.M `json:"parameters"`
+}
+
+func (n *Neo4jSink) commit(stmts []cypherStatement) error {
+	if len(stmts) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(bson.M{"statements": stmts})
+	if err != nil {
+		return err
+	}
+
+	resp, err := n.client.Post(n.url+"/db/"+n.database+"/tx/commit", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("sinks: neo4j transaction failed with status %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+// Package sinks lets enumeration results be fanned out to one or more
+// destinations instead of the single hardcoded Mongo write the pipeline
+// used to perform. Operators choose which sinks are active through
+// FromEnv; new destinations are added by implementing the Sink interface.
+package sinks
+
+import (
+	"github.com/OWASP/Amass/amass"
+	"github.com/OWASP/Amass/amass/handlers"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Result is what a Sink receives for each resolved name an enumeration
+// produces.
+type Result struct {
+	ID     bson.ObjectId
+	Domain string
+	Output amass.AmassOutput
+}
+
+// Sink is a destination for enumeration results. Implementations must be
+// safe for concurrent use, since a single enumeration's results may be fanned
+// out to several sinks at once.
+type Sink interface {
+	// Ingest records a single resolved name.
+	Ingest(r Result) error
+
+	// MarkFinished records that the job identified by id has completed.
+	MarkFinished(id bson.ObjectId) error
+
+	// Close releases any resources held by the sink.
+	Close() error
+}
+
+// GraphSink is implemented by sinks that also want the full graph an
+// enumeration built, once it has finished running. Sinks that only care
+// about the per-name stream (the common case) need not implement it.
+type GraphSink interface {
+	Sink
+
+	// IngestGraph materializes graph into the sink's storage.
+	IngestGraph(domain string, graph *handlers.Graph) error
+}
+
+// MultiSink fans a result out to every sink it wraps, continuing on to the
+// rest even if one fails, and returning the first error encountered.
+type MultiSink struct {
+	sinks []Sink
+}
+
+// NewMultiSink returns a Sink that writes to every sink in sinks.
+func NewMultiSink(sinks ...Sink) *MultiSink {
+	return &MultiSink{sinks: sinks}
+}
+
+// Sinks returns the sinks wrapped by ms, for callers that need to reach a
+// concrete sink (e.g. to type-assert for GraphSink).
+func (ms *MultiSink) Sinks() []Sink {
+	return ms.sinks
+}
+
+func (ms *MultiSink) Ingest(r Result) error {
+	var first error
+	for _, s := range ms.sinks {
+		if err := s.Ingest(r); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+func (ms *MultiSink) MarkFinished(id bson.ObjectId) error {
+	var first error
+	for _, s := range ms.sinks {
+		if err := s.MarkFinished(id); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// IngestGraph forwards graph to every wrapped sink that implements GraphSink.
+func (ms *MultiSink) IngestGraph(domain string, graph *handlers.Graph) error {
+	var first error
+	for _, s := range ms.sinks {
+		gs, ok := s.(GraphSink)
+		if !ok {
+			continue
+		}
+		if err := gs.IngestGraph(domain, graph); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+func (ms *MultiSink) Close() error {
+	var first error
+	for _, s := range ms.sinks {
+		if err := s.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
@@ -0,0 +1,92 @@
+package tracker
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseSince parses durations like Go's time.ParseDuration, plus a "d" unit
+// for days (e.g. "7d"), which is the unit operators actually want when
+// asking "what changed in the last week".
+func ParseSince(s string) (time.Duration, error) {
+	if strings.HasSuffix(s, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(s, "d"))
+		if err != nil {
+			return 0, fmt.Errorf("tracker: invalid duration %q: %v", s, err)
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(s)
+}
+
+// RunCLI implements the "blazeRecon track" subcommand: it prints the diff
+// for a domain over the given --since window to stdout as JSON.
+func (s *Store) RunCLI(args []string) error {
+	fs := flag.NewFlagSet("track", flag.ExitOnError)
+	domain := fs.String("domain", "", "domain to diff")
+	since := fs.String("since", "24h", "how far back to diff from, e.g. 7d, 12h")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *domain == "" {
+		return errors.New("tracker: --domain is required")
+	}
+
+	dur, err := ParseSince(*since)
+	if err != nil {
+		return err
+	}
+
+	to := time.Now()
+	diff, err := s.Diff(*domain, to.Add(-dur), to)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(diff)
+}
+
+// HTTPHandler serves GET /diff?domain=...&since=7d as JSON, mirroring the
+// CLI subcommand for pipelines that would rather poll an endpoint.
+func (s *Store) HTTPHandler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/diff", func(w http.ResponseWriter, r *http.Request) {
+		domain := r.URL.Query().Get("domain")
+		if domain == "" {
+			http.Error(w, "domain is required", http.StatusBadRequest)
+			return
+		}
+
+		since := r.URL.Query().Get("since")
+		if since == "" {
+			since = "24h"
+		}
+		dur, err := ParseSince(since)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		to := time.Now()
+		diff, err := s.Diff(domain, to.Add(-dur), to)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(diff)
+	})
+
+	return mux
+}
@@ -0,0 +1,38 @@
+package tracker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSince(t *testing.T) {
+	cases := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"24h", 24 * time.Hour, false},
+		{"7d", 7 * 24 * time.Hour, false},
+		{"30m", 30 * time.Minute, false},
+		{"0d", 0, false},
+		{"nope", 0, true},
+		{"xd", 0, true},
+	}
+
+	for _, c := range cases {
+		got, err := ParseSince(c.in)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("ParseSince(%q) = %v, want an error", c.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseSince(%q) returned unexpected error: %v", c.in, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("ParseSince(%q) = %v, want %v", c.in, got, c.want)
+		}
+	}
+}
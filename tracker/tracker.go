@@ -0,0 +1,225 @@
+// Package tracker keeps a historical time series of enumeration results so
+// that two runs against the same domain can be diffed against one another.
+// It is the analogue of Amass's own "amass.tracker" command, but reads from
+// the same Mongo collections that the recon pipeline already writes to.
+package tracker
+
+import (
+	"errors"
+	"time"
+
+	"github.com/OWASP/Amass/amass"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// SnapshotsCollection holds one document per (domain, runID) enumeration.
+const SnapshotsCollection = "snapshots"
+
+// LatestCollection mirrors the most recent snapshot per domain so that
+// lookups for "what do we currently know" don't have to scan the history.
+const LatestCollection = "latest"
+
+// NameRecord is everything learned about a single resolved name during one
+// enumeration run.
+type NameRecord struct {
+	Name      string   `bson:"name" json:"name"`
+	Addresses []string `bson:"addresses" json:"addresses"`
+	ASN       int      `bson:"asn" json:"asn"`
+}
+
+// Snapshot is a single enumeration run recorded against a domain.
+type Snapshot struct {
+	Domain    string        `bson:"domain"`
+	RunID     bson.ObjectId `bson:"run_id"`
+	Timestamp time.Time     `bson:"timestamp"`
+	Names     []NameRecord  `bson:"names"`
+}
+
+// Store persists enumeration snapshots and answers diff queries against them.
+type Store struct {
+	session *mgo.Session
+}
+
+// NewStore returns a Store backed by the given Mongo session.
+func NewStore(session *mgo.Session) *Store {
+	return &Store{session: session}
+}
+
+func (s *Store) snapshots() *mgo.Collection {
+	return s.session.DB("test").C(SnapshotsCollection)
+}
+
+func (s *Store) latest() *mgo.Collection {
+	return s.session.DB("test").C(LatestCollection)
+}
+
+// Seen reports whether domain has a prior recorded run, so callers can tell
+// a first-time enumeration apart from a rescan.
+func (s *Store) Seen(domain string) bool {
+	n, err := s.latest().Find(bson.M{"domain": domain}).Count()
+	return err == nil && n > 0
+}
+
+// Ingest folds a single enumeration result into the in-progress snapshot for
+// (domain, runID), creating the snapshot document on first use.
+func (s *Store) Ingest(domain string, runID bson.ObjectId, out *amass.AmassOutput) error {
+	record := NameRecord{Name: out.Name}
+	for i, a := range out.Addresses {
+		record.Addresses = append(record.Addresses, a.Address.String())
+		if i == 0 {
+			record.ASN = a.ASN
+		}
+	}
+
+	_, err := s.snapshots().Upsert(
+		bson.M{"domain": domain, "run_id": runID},
+		bson.M{
+			"$setOnInsert": bson.M{"domain": domain, "run_id": runID, "timestamp": time.Now()},
+			"$push":        bson.M{"names": record},
+		},
+	)
+	return err
+}
+
+// FindRun returns the snapshot recorded for the given run ID, regardless of
+// domain, so that callers (e.g. the viz CLI) can reconstruct a single run's
+// results from nothing but the ID they were given.
+func (s *Store) FindRun(runID bson.ObjectId) (*Snapshot, error) {
+	var snap Snapshot
+
+	err := s.snapshots().Find(bson.M{"run_id": runID}).One(&snap)
+	if err == mgo.ErrNotFound {
+		return nil, errors.New("tracker: no snapshot found for run " + runID.Hex())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &snap, nil
+}
+
+// Finish copies the completed snapshot for (domain, runID) into the latest
+// view, so that it is returned without scanning run history. A run that
+// ingested no results never created a snapshot document - Ingest is what
+// creates it, on the first result - so a missing snapshot here is treated as
+// an empty one rather than a failure, so the run is still recorded as
+// finished.
+func (s *Store) Finish(domain string, runID bson.ObjectId) error {
+	var snap Snapshot
+
+	err := s.snapshots().Find(bson.M{"domain": domain, "run_id": runID}).One(&snap)
+	if err == mgo.ErrNotFound {
+		snap = Snapshot{Domain: domain, RunID: runID, Timestamp: time.Now()}
+	} else if err != nil {
+		return err
+	}
+
+	_, err = s.latest().Upsert(bson.M{"domain": domain}, snap)
+	return err
+}
+
+// Diff describes what changed between two snapshots of the same domain.
+type Diff struct {
+	Domain       string   `json:"domain"`
+	From         string   `json:"from"`
+	To           string   `json:"to"`
+	AddedNames   []string `json:"added_names"`
+	RemovedNames []string `json:"removed_names"`
+	ChangedAddrs []string `json:"changed_addresses"`
+	ChangedASNs  []string `json:"changed_asns"`
+}
+
+// Diff returns the changes between the snapshots closest to, at or before,
+// fromTime and toTime, keyed by timestamp rather than run ID so that
+// callers can diff by time window alone.
+func (s *Store) Diff(domain string, fromTime, toTime time.Time) (*Diff, error) {
+	from, err := s.snapshotAt(domain, fromTime)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.snapshotAt(domain, toTime)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Diff{
+		Domain: domain,
+		From:   from.Timestamp.Format(time.RFC3339),
+		To:     to.Timestamp.Format(time.RFC3339),
+	}
+
+	fromNames := make(map[string]NameRecord, len(from.Names))
+	for _, r := range from.Names {
+		fromNames[r.Name] = r
+	}
+	toNames := make(map[string]NameRecord, len(to.Names))
+	for _, r := range to.Names {
+		toNames[r.Name] = r
+	}
+
+	for name, rec := range toNames {
+		prev, existed := fromNames[name]
+		if !existed {
+			d.AddedNames = append(d.AddedNames, name)
+			continue
+		}
+		if !sameStrings(rec.Addresses, prev.Addresses) {
+			d.ChangedAddrs = append(d.ChangedAddrs, name)
+		}
+		if rec.ASN != prev.ASN {
+			d.ChangedASNs = append(d.ChangedASNs, name)
+		}
+	}
+	for name := range fromNames {
+		if _, stillPresent := toNames[name]; !stillPresent {
+			d.RemovedNames = append(d.RemovedNames, name)
+		}
+	}
+
+	return d, nil
+}
+
+// snapshotAt returns the most recent snapshot for domain at or before t. When
+// every snapshot for domain postdates t (e.g. a domain first enumerated
+// yesterday, diffed with --since 7d), it falls back to the earliest snapshot
+// on record instead of failing, so the first diff against a young domain
+// reports against its oldest known state rather than erroring out.
+func (s *Store) snapshotAt(domain string, t time.Time) (*Snapshot, error) {
+	var snap Snapshot
+
+	err := s.snapshots().Find(bson.M{
+		"domain":    domain,
+		"timestamp": bson.M{"$lte": t},
+	}).Sort("-timestamp").One(&snap)
+	if err == nil {
+		return &snap, nil
+	}
+	if err != mgo.ErrNotFound {
+		return nil, err
+	}
+
+	err = s.snapshots().Find(bson.M{"domain": domain}).Sort("timestamp").One(&snap)
+	if err == mgo.ErrNotFound {
+		return nil, errors.New("tracker: no snapshot found for " + domain)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &snap, nil
+}
+
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	set := make(map[string]bool, len(a))
+	for _, s := range a {
+		set[s] = true
+	}
+	for _, s := range b {
+		if !set[s] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,23 @@
+package tracker
+
+import "testing"
+
+func TestSameStrings(t *testing.T) {
+	cases := []struct {
+		name string
+		a, b []string
+		want bool
+	}{
+		{"equal, different order", []string{"a", "b"}, []string{"b", "a"}, true},
+		{"both empty", nil, nil, true},
+		{"different lengths", []string{"a"}, []string{"a", "b"}, false},
+		{"same length, different contents", []string{"a", "b"}, []string{"a", "c"}, false},
+		{"duplicate collapses a set, length still differs", []string{"a", "a"}, []string{"a"}, false},
+	}
+
+	for _, c := range cases {
+		if got := sameStrings(c.a, c.b); got != c.want {
+			t.Errorf("%s: sameStrings(%v, %v) = %v, want %v", c.name, c.a, c.b, got, c.want)
+		}
+	}
+}
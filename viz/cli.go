@@ -0,0 +1,58 @@
+package viz
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"strings"
+
+	"InstaStellar/tracker"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+// RunCLI implements the "blazeRecon viz" subcommand: it pulls the stored
+// run identified by --id out of Mongo, reconstructs its graph, and writes
+// it to --out as DOT (or as SVG, when --out ends in .svg and GraphViz's
+// `dot` binary is on PATH).
+func RunCLI(store *tracker.Store, args []string) error {
+	fs := flag.NewFlagSet("viz", flag.ExitOnError)
+	id := fs.String("id", "", "Mongo run ID of the enumeration to render")
+	out := fs.String("out", "graph.dot", "output file (.dot or .svg)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *id == "" {
+		return errors.New("viz: --id is required")
+	}
+	if !bson.IsObjectIdHex(*id) {
+		return errors.New("viz: --id is not a valid Mongo ObjectId")
+	}
+
+	snap, err := store.FindRun(bson.ObjectIdHex(*id))
+	if err != nil {
+		return err
+	}
+
+	dotPath := *out
+	if strings.HasSuffix(*out, ".svg") {
+		dotPath = strings.TrimSuffix(*out, ".svg") + ".dot"
+	}
+
+	f, err := os.Create(dotPath)
+	if err != nil {
+		return err
+	}
+	if err := WriteSnapshotDOT(f, snap); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	if strings.HasSuffix(*out, ".svg") {
+		return RenderSVG(dotPath, *out)
+	}
+	return nil
+}
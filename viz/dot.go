@@ -0,0 +1,158 @@
+// Package viz renders the graphs Amass builds during enumeration into
+// GraphViz DOT format, so operators can inspect a run visually instead of
+// only through Mongo queries.
+package viz
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"InstaStellar/depgraph/graphwalk"
+	"InstaStellar/tracker"
+
+	"github.com/OWASP/Amass/amass/handlers"
+)
+
+// node/edge styling, one line per node type, matched to what the DOT spec
+// in the backlog asked for: domain=box, subdomain=oval, IP=diamond,
+// netblock=hexagon, ASN=cluster.
+const (
+	domainShape    = "box"
+	subdomainShape = "oval"
+	ipShape        = "diamond"
+	netblockShape  = "hexagon"
+
+	domainColor    = "lightblue"
+	subdomainColor = "white"
+	ipColor        = "lightyellow"
+	netblockColor  = "lightgreen"
+	asnColor       = "lightgrey"
+)
+
+// WriteDOT renders the graph built for domain by a live Enumeration into
+// DOT format.
+func WriteDOT(w io.Writer, domain string, hg *handlers.Graph) error {
+	if hg == nil {
+		return fmt.Errorf("viz: no graph to render for %s", domain)
+	}
+
+	bw := &dotWriter{w: w}
+	bw.header()
+	bw.node(domain, domainShape, domainColor)
+
+	asnClusters := make(map[int][]string)
+
+	for _, sub := range graphwalk.Subdomains(hg, domain) {
+		bw.node(sub, subdomainShape, subdomainColor)
+		bw.edge(domain, sub, "resolves_to")
+
+		for _, cname := range graphwalk.CNAMERecords(hg, sub) {
+			bw.node(cname, subdomainShape, subdomainColor)
+			bw.edge(sub, cname, "cname")
+		}
+
+		for _, ns := range graphwalk.NSRecords(hg, sub) {
+			bw.node(ns, subdomainShape, subdomainColor)
+			bw.edge(sub, ns, "ns")
+		}
+
+		for _, addr := range graphwalk.Addresses(hg, sub) {
+			bw.node(addr, ipShape, ipColor)
+			bw.edge(sub, addr, "resolves_to")
+
+			if netblock := graphwalk.Netblock(hg, addr); netblock != "" {
+				bw.node(netblock, netblockShape, netblockColor)
+				bw.edge(addr, netblock, "in_netblock")
+			}
+
+			if asn, desc := graphwalk.ASNInfo(hg, addr); asn != 0 {
+				asnClusters[asn] = append(asnClusters[asn], addr)
+				bw.asnDesc[asn] = desc
+			}
+		}
+	}
+
+	for asn, addrs := range asnClusters {
+		bw.asnCluster(asn, addrs)
+	}
+
+	bw.footer()
+	return bw.err
+}
+
+// WriteSnapshotDOT renders a tracker.Snapshot - a previously persisted
+// enumeration run pulled back out of Mongo - into DOT format. Snapshots
+// don't retain CNAME or nameserver edges, so only the subdomain/address/ASN
+// relationships are drawn.
+func WriteSnapshotDOT(w io.Writer, snap *tracker.Snapshot) error {
+	bw := &dotWriter{w: w}
+	bw.header()
+	bw.node(snap.Domain, domainShape, domainColor)
+
+	asnClusters := make(map[int][]string)
+
+	for _, rec := range snap.Names {
+		bw.node(rec.Name, subdomainShape, subdomainColor)
+		bw.edge(snap.Domain, rec.Name, "resolves_to")
+
+		for _, addr := range rec.Addresses {
+			bw.node(addr, ipShape, ipColor)
+			bw.edge(rec.Name, addr, "resolves_to")
+		}
+
+		if rec.ASN != 0 {
+			for _, addr := range rec.Addresses {
+				asnClusters[rec.ASN] = append(asnClusters[rec.ASN], addr)
+			}
+		}
+	}
+
+	for asn, addrs := range asnClusters {
+		bw.asnCluster(asn, addrs)
+	}
+
+	bw.footer()
+	return bw.err
+}
+
+// dotWriter accumulates DOT output, tracking the first error encountered so
+// call sites don't need to check every write.
+type dotWriter struct {
+	w       io.Writer
+	err     error
+	asnDesc map[int]string
+}
+
+func (d *dotWriter) header() {
+	d.asnDesc = make(map[int]string)
+	d.write("digraph blazeRecon {\n  rankdir=LR;\n  node [fontsize=10];\n")
+}
+
+func (d *dotWriter) footer() {
+	d.write("}\n")
+}
+
+func (d *dotWriter) node(name, shape, color string) {
+	d.write(fmt.Sprintf("  %q [shape=%s, style=filled, fillcolor=%s];\n", name, shape, color))
+}
+
+func (d *dotWriter) edge(from, to, label string) {
+	d.write(fmt.Sprintf("  %q -> %q [label=%q];\n", from, to, label))
+}
+
+func (d *dotWriter) asnCluster(asn int, addrs []string) {
+	d.write(fmt.Sprintf("  subgraph cluster_asn_%d {\n    label=%q;\n    color=%s;\n",
+		asn, "AS"+strconv.Itoa(asn)+" "+d.asnDesc[asn], asnColor))
+	for _, addr := range addrs {
+		d.write(fmt.Sprintf("    %q;\n", addr))
+	}
+	d.write("  }\n")
+}
+
+func (d *dotWriter) write(s string) {
+	if d.err != nil {
+		return
+	}
+	_, d.err = io.WriteString(d.w, s)
+}
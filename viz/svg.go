@@ -0,0 +1,22 @@
+package viz
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// RenderSVG shells out to the `dot` binary to convert a DOT file into an
+// SVG, for operators who have GraphViz installed and would rather not
+// render DOT themselves. It returns an error naming the missing binary if
+// `dot` isn't on PATH.
+func RenderSVG(dotPath, svgPath string) error {
+	if _, err := exec.LookPath("dot"); err != nil {
+		return fmt.Errorf("viz: the GraphViz `dot` binary is required for SVG output: %v", err)
+	}
+
+	cmd := exec.Command("dot", "-Tsvg", "-o", svgPath, dotPath)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("viz: dot failed: %v: %s", err, out)
+	}
+	return nil
+}